@@ -0,0 +1,110 @@
+// Command jiralert runs an Alertmanager webhook receiver that files issues
+// against a configurable tracker backend (JIRA, GitLab, GitHub, or Gitea).
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/free/jiralert/pkg/alertmanager"
+	"github.com/free/jiralert/pkg/config"
+	"github.com/free/jiralert/pkg/logging"
+	"github.com/free/jiralert/pkg/notify/common"
+	"github.com/free/jiralert/pkg/notify/gitea"
+	"github.com/free/jiralert/pkg/notify/github"
+	"github.com/free/jiralert/pkg/notify/gitlab"
+	"github.com/free/jiralert/pkg/notify/jira"
+	"github.com/free/jiralert/pkg/template"
+)
+
+var (
+	listenAddress = flag.String("listen-address", ":9097", "The address to listen on for HTTP requests.")
+	configFile    = flag.String("config", "jiralert.yml", "The JIRAlert configuration file")
+	logLevelFlag  = flag.String("log.level", "info", "Log filtering level (debug, info, warn, error)")
+	logFormatFlag = flag.String("log.format", "logfmt", "Log format (logfmt, json)")
+)
+
+func main() {
+	flag.Parse()
+
+	logger := slog.New(logging.NewHandler(*logFormatFlag, *logLevelFlag))
+	ctx := context.Background()
+
+	conf, err := config.LoadFile(*configFile)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "error loading configuration", slog.Any("err", err))
+		os.Exit(1)
+	}
+	tmpl, err := template.LoadTemplate(conf.Template)
+	if err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "error loading templates", slog.Any("err", err))
+		os.Exit(1)
+	}
+
+	notifiers := make(map[string]common.Notifier, len(conf.Receivers))
+	for _, rc := range conf.Receivers {
+		n, err := newNotifier(rc, tmpl)
+		if err != nil {
+			logger.LogAttrs(ctx, slog.LevelError, "error configuring receiver", slog.String("receiver", rc.Name), slog.Any("err", err))
+			os.Exit(1)
+		}
+		notifiers[rc.Name] = n
+	}
+
+	http.HandleFunc("/alert", func(w http.ResponseWriter, req *http.Request) {
+		handleWebhook(w, req, notifiers, logger)
+	})
+
+	logger.LogAttrs(ctx, slog.LevelInfo, "listening", slog.String("address", *listenAddress))
+	if err := http.ListenAndServe(*listenAddress, nil); err != nil {
+		logger.LogAttrs(ctx, slog.LevelError, "error running server", slog.Any("err", err))
+		os.Exit(1)
+	}
+}
+
+// newNotifier constructs the common.Notifier implementation selected by rc.Kind.
+func newNotifier(rc *config.ReceiverConfig, tmpl *template.Template) (common.Notifier, error) {
+	switch rc.Kind {
+	case config.KindGitLab:
+		return gitlab.New(rc, tmpl)
+	case config.KindGitHub:
+		return github.New(rc, tmpl)
+	case config.KindGitea:
+		return gitea.New(rc, tmpl)
+	case config.KindJIRA:
+		return jira.New(rc, tmpl)
+	default:
+		return nil, fmt.Errorf("receiver %q: unknown kind %q", rc.Name, rc.Kind)
+	}
+}
+
+// handleWebhook decodes one Alertmanager webhook payload and dispatches it to the receiver it names.
+func handleWebhook(w http.ResponseWriter, req *http.Request, notifiers map[string]common.Notifier, logger *slog.Logger) {
+	var data alertmanager.Data
+	if err := json.NewDecoder(req.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	notifier, ok := notifiers[data.Receiver]
+	if !ok {
+		http.Error(w, fmt.Sprintf("receiver %q not configured", data.Receiver), http.StatusNotFound)
+		return
+	}
+
+	if retry, err := notifier.Notify(&data, logger); err != nil {
+		logger.LogAttrs(req.Context(), slog.LevelError, "error notifying", slog.String("receiver", data.Receiver), slog.Any("err", err))
+		if retry {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,71 @@
+// Package alertmanager holds the types used to unmarshal Alertmanager webhook payloads.
+package alertmanager
+
+import "sort"
+
+// Data is the entirety of the Alertmanager webhook payload, as documented in
+// https://prometheus.io/docs/alerting/configuration/#webhook_config.
+type Data struct {
+	Receiver string `json:"receiver"`
+	Status   string `json:"status"`
+	Alerts   Alerts `json:"alerts"`
+
+	GroupLabels       KV `json:"groupLabels"`
+	CommonLabels      KV `json:"commonLabels"`
+	CommonAnnotations KV `json:"commonAnnotations"`
+
+	ExternalURL string `json:"externalURL"`
+}
+
+// Alert holds one alert for notification templates.
+type Alert struct {
+	Status       string `json:"status"`
+	Labels       KV     `json:"labels"`
+	Annotations  KV     `json:"annotations"`
+	StartsAt     string `json:"startsAt"`
+	EndsAt       string `json:"endsAt"`
+	GeneratorURL string `json:"generatorURL"`
+}
+
+// Alerts is a list of Alert, having a Firing and Resolved method to filter by status.
+type Alerts []Alert
+
+// Firing returns the subset of alerts that are firing.
+func (as Alerts) Firing() []Alert {
+	return as.Filter(func(a Alert) bool { return a.Status == "firing" })
+}
+
+// Resolved returns the subset of alerts that are resolved.
+func (as Alerts) Resolved() []Alert {
+	return as.Filter(func(a Alert) bool { return a.Status == "resolved" })
+}
+
+// Filter returns the subset of alerts matching the predicate.
+func (as Alerts) Filter(f func(a Alert) bool) []Alert {
+	filtered := make(Alerts, 0, len(as))
+	for _, a := range as {
+		if f(a) {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered
+}
+
+// KV is a set of key/value string pairs, e.g. labels or annotations.
+type KV map[string]string
+
+// Pair is a name/value string pair.
+type Pair struct {
+	Name  string
+	Value string
+}
+
+// SortedPairs returns a sorted slice of name/value pairs, for deterministic rendering.
+func (kv KV) SortedPairs() []Pair {
+	pairs := make([]Pair, 0, len(kv))
+	for k, v := range kv {
+		pairs = append(pairs, Pair{Name: k, Value: v})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+	return pairs
+}
@@ -0,0 +1,297 @@
+// Package config implements the YAML configuration for jiralert.
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Secret is a string that must not be revealed on marshal.
+type Secret string
+
+// MarshalYAML implements the yaml.Marshaler interface for Secret.
+func (s Secret) MarshalYAML() (interface{}, error) {
+	if s != "" {
+		return "<secret>", nil
+	}
+	return "", nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for Secret.
+func (s *Secret) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain Secret
+	return unmarshal((*plain)(s))
+}
+
+// Resolve returns the secret's actual value: secretFile, if set, is read and
+// takes precedence over s (mirroring auth.oauth_private_key_path's file
+// indirection for the OAuth private key); otherwise a value of the form
+// "env:NAME" is resolved from the named environment variable; otherwise s is
+// used as-is.
+func (s Secret) Resolve(secretFile string) (string, error) {
+	if secretFile != "" {
+		b, err := ioutil.ReadFile(secretFile)
+		if err != nil {
+			return "", fmt.Errorf("reading secret file %q: %w", secretFile, err)
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
+	if name, ok := strings.CutPrefix(string(s), "env:"); ok {
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("env var %q referenced by secret is not set", name)
+		}
+		return v, nil
+	}
+	return string(s), nil
+}
+
+// Duration wraps time.Duration and adds YAML support, parsed the same way
+// Go itself parses duration strings (e.g. "5m", "1h30m").
+type Duration time.Duration
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for Duration.
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface for Duration.
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+// AuthMode selects how the JIRA client authenticates against the API.
+type AuthMode string
+
+const (
+	// AuthBasic uses a JIRA username and password (or API token used as a password).
+	AuthBasic AuthMode = "basic"
+	// AuthPAT uses a bearer Personal Access Token, as required by Jira Data Center
+	// instances that have disabled basic auth, and supported by Jira Cloud API tokens.
+	AuthPAT AuthMode = "pat"
+	// AuthOAuth uses a two-legged OAuth1/JWT flow with a consumer key and private key.
+	AuthOAuth AuthMode = "oauth"
+)
+
+// AuthConfig selects and configures the authentication mode used to talk to JIRA.
+type AuthConfig struct {
+	Mode AuthMode `yaml:"mode,omitempty"`
+
+	// Used when Mode is AuthBasic. Password can also be given as "env:NAME"
+	// to read it from an environment variable, or via PasswordFile to read
+	// it from a file.
+	User         string `yaml:"user,omitempty"`
+	Password     Secret `yaml:"password,omitempty"`
+	PasswordFile string `yaml:"password_file,omitempty"`
+
+	// Used when Mode is AuthPAT. Same "env:NAME"/file resolution as Password.
+	PersonalAccessToken     Secret `yaml:"personal_access_token,omitempty"`
+	PersonalAccessTokenFile string `yaml:"personal_access_token_file,omitempty"`
+
+	// Used when Mode is AuthOAuth.
+	OAuthConsumerKey    string `yaml:"oauth_consumer_key,omitempty"`
+	OAuthPrivateKeyPath string `yaml:"oauth_private_key_path,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, defaulting Mode to
+// AuthBasic so existing user/password configs keep working unchanged.
+func (a *AuthConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain AuthConfig
+	if err := unmarshal((*plain)(a)); err != nil {
+		return err
+	}
+	if a.Mode == "" {
+		a.Mode = AuthBasic
+	}
+	switch a.Mode {
+	case AuthBasic, AuthPAT, AuthOAuth:
+	default:
+		return fmt.Errorf("unknown auth mode %q", a.Mode)
+	}
+	return nil
+}
+
+// Regexp wraps regexp.Regexp to add YAML support, anchoring the pattern on
+// both ends so "foo" doesn't accidentally match "foobar".
+type Regexp struct {
+	*regexp.Regexp
+	original string
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for Regexp.
+func (re *Regexp) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	r, err := regexp.Compile("^(?:" + s + ")$")
+	if err != nil {
+		return err
+	}
+	re.Regexp = r
+	re.original = s
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface for Regexp.
+func (re Regexp) MarshalYAML() (interface{}, error) {
+	return re.original, nil
+}
+
+// FilterAction is what to do with an alert group matched by a FilterRule.
+type FilterAction string
+
+const (
+	// FilterActionDrop discards the alert group without touching JIRA.
+	FilterActionDrop FilterAction = "drop"
+	// FilterActionAllow lets the alert group through to Notify's normal search/create flow.
+	FilterActionAllow FilterAction = "allow"
+)
+
+// FilterRule is one entry of a Filters list, matched against a group's
+// GroupLabels/CommonAnnotations and, failing that, against each of its
+// Alerts' Labels/Annotations in turn.
+type FilterRule struct {
+	Match              map[string]string `yaml:"match,omitempty"`
+	MatchRE            map[string]Regexp `yaml:"match_re,omitempty"`
+	MatchAnnotations   map[string]string `yaml:"match_annotations,omitempty"`
+	MatchAnnotationsRE map[string]Regexp `yaml:"match_annotations_re,omitempty"`
+	SeverityIn         []string          `yaml:"severity_in,omitempty"`
+	Action             FilterAction      `yaml:"action"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for FilterRule.
+func (f *FilterRule) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain FilterRule
+	if err := unmarshal((*plain)(f)); err != nil {
+		return err
+	}
+	switch f.Action {
+	case FilterActionDrop, FilterActionAllow:
+	default:
+		return fmt.Errorf("filters: action must be %q or %q, got %q", FilterActionDrop, FilterActionAllow, f.Action)
+	}
+	return nil
+}
+
+// ReceiverKind selects which issue tracker backend a receiver talks to.
+type ReceiverKind string
+
+const (
+	// KindJIRA is the default, backwards-compatible backend.
+	KindJIRA   ReceiverKind = "jira"
+	KindGitLab ReceiverKind = "gitlab"
+	KindGitHub ReceiverKind = "github"
+	KindGitea  ReceiverKind = "gitea"
+)
+
+// ReceiverConfig is the configuration for one receiver, i.e. one (tracker, project, template) tuple.
+// Most fields are shared across backends; a backend ignores whatever it has no use for.
+type ReceiverConfig struct {
+	Name string       `yaml:"name"`
+	Kind ReceiverKind `yaml:"kind,omitempty"`
+
+	APIURL string     `yaml:"api_url"`
+	Auth   AuthConfig `yaml:"auth,omitempty"`
+
+	// Deprecated: use Auth instead. Kept so existing basic-auth configs keep working.
+	User     string `yaml:"user,omitempty"`
+	Password Secret `yaml:"password,omitempty"`
+
+	Project     string   `yaml:"project"`
+	IssueType   string   `yaml:"issue_type"`
+	Summary     string   `yaml:"summary"`
+	Description string   `yaml:"description,omitempty"`
+	Priority    string   `yaml:"priority,omitempty"`
+	Components  []string `yaml:"components,omitempty"`
+
+	AddGroupLabels bool `yaml:"add_group_labels"`
+
+	LabelKey       string `yaml:"label_key,omitempty"`
+	GroupFieldName string `yaml:"group_field_name,omitempty"`
+	GroupFieldID   string `yaml:"group_field_id,omitempty"`
+
+	ReopenState       string    `yaml:"reopen_state,omitempty"`
+	ReopenDuration    *Duration `yaml:"reopen_duration,omitempty"`
+	WontFixResolution string    `yaml:"wont_fix_resolution,omitempty"`
+
+	AutoResolve       bool   `yaml:"auto_resolve"`
+	ResolveTransition string `yaml:"resolve_transition,omitempty"`
+	ResolveResolution string `yaml:"resolve_resolution,omitempty"`
+	ResolveComment    string `yaml:"resolve_comment,omitempty"`
+	// ResolveIgnoreAssignee opts back into auto-resolving an issue that has
+	// been assigned to someone, which is otherwise treated as a sign that a
+	// human is already handling it and overriding their work would surprise
+	// them.
+	ResolveIgnoreAssignee bool `yaml:"resolve_ignore_assignee,omitempty"`
+
+	UpdateInPlace   bool              `yaml:"update_in_place"`
+	CommentTemplate string            `yaml:"comment_template,omitempty"`
+	UpdateFields    map[string]string `yaml:"update_fields,omitempty"`
+
+	Filters             []FilterRule      `yaml:"filters,omitempty"`
+	SeverityPriorityMap map[string]string `yaml:"severity_priority_map,omitempty"`
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface, defaulting Kind to
+// KindJIRA so existing configs keep working unchanged.
+func (r *ReceiverConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	type plain ReceiverConfig
+	if err := unmarshal((*plain)(r)); err != nil {
+		return err
+	}
+	if r.Kind == "" {
+		r.Kind = KindJIRA
+	}
+	switch r.Kind {
+	case KindJIRA, KindGitLab, KindGitHub, KindGitea:
+	default:
+		return fmt.Errorf("receiver %q: unknown kind %q", r.Name, r.Kind)
+	}
+	return nil
+}
+
+// Config is the top-level jiralert configuration.
+type Config struct {
+	Defaults  *ReceiverConfig   `yaml:"defaults,omitempty"`
+	Receivers []*ReceiverConfig `yaml:"receivers"`
+	Template  string            `yaml:"template"`
+}
+
+// LoadFile parses and validates the named YAML config file.
+func LoadFile(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := yaml.UnmarshalStrict(b, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// ReceiverByName returns the named receiver, or nil if it does not exist.
+func (c *Config) ReceiverByName(name string) *ReceiverConfig {
+	for _, r := range c.Receivers {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecretResolve(t *testing.T) {
+	t.Run("literal value is used as-is", func(t *testing.T) {
+		got, err := Secret("hunter2").Resolve("")
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if got != "hunter2" {
+			t.Errorf("Resolve() = %q, want %q", got, "hunter2")
+		}
+	})
+
+	t.Run("env: prefix resolves from the environment", func(t *testing.T) {
+		t.Setenv("JIRALERT_TEST_SECRET", "from-env")
+		got, err := Secret("env:JIRALERT_TEST_SECRET").Resolve("")
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if got != "from-env" {
+			t.Errorf("Resolve() = %q, want %q", got, "from-env")
+		}
+	})
+
+	t.Run("env: prefix errors when the variable is unset", func(t *testing.T) {
+		os.Unsetenv("JIRALERT_TEST_SECRET_UNSET")
+		if _, err := Secret("env:JIRALERT_TEST_SECRET_UNSET").Resolve(""); err == nil {
+			t.Fatal("Resolve() succeeded, want error for unset env var")
+		}
+	})
+
+	t.Run("secretFile takes precedence and is trimmed", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "secret")
+		if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+			t.Fatalf("writing secret file: %v", err)
+		}
+		got, err := Secret("literal").Resolve(path)
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if got != "from-file" {
+			t.Errorf("Resolve() = %q, want %q", got, "from-file")
+		}
+	})
+}
@@ -0,0 +1,107 @@
+// Package logging bootstraps jiralert's slog.Handler: a JSON or logfmt-style
+// text handler at a configurable level, wrapped to collapse repeated
+// identical lines.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// NewHandler builds the handler selected by format ("json" or "logfmt") and
+// level ("debug", "info", "warn", "error"), writing to stderr.
+func NewHandler(format, level string) slog.Handler {
+	opts := &slog.HandlerOptions{Level: parseLevel(level)}
+	switch format {
+	case "json":
+		return NewDedupingHandler(slog.NewJSONHandler(os.Stderr, opts))
+	default:
+		return NewDedupingHandler(slog.NewTextHandler(os.Stderr, opts))
+	}
+}
+
+func parseLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// DedupingHandler wraps a slog.Handler and drops a record that is identical
+// (level, message, and attributes) to the one immediately preceding it.
+// Alertmanager re-notifies every few minutes for as long as a group stays
+// firing, and most of those notifications produce the exact same log line
+// (e.g. "issue is unresolved, nothing to do") — without this, that line
+// floods the log for the full lifetime of the incident.
+type DedupingHandler struct {
+	next  slog.Handler
+	mu    *sync.Mutex
+	last  *string
+	scope string // attributes and groups added via WithAttrs/WithGroup, rendered
+}
+
+// NewDedupingHandler wraps next.
+func NewDedupingHandler(next slog.Handler) *DedupingHandler {
+	empty := ""
+	return &DedupingHandler{next: next, mu: &sync.Mutex{}, last: &empty}
+}
+
+// Enabled implements the slog.Handler interface.
+func (h *DedupingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements the slog.Handler interface.
+func (h *DedupingHandler) Handle(ctx context.Context, r slog.Record) error {
+	key := h.scope + fingerprint(r)
+
+	h.mu.Lock()
+	duplicate := *h.last == key
+	*h.last = key
+	h.mu.Unlock()
+
+	if duplicate {
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *DedupingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	scope := h.scope
+	for _, a := range attrs {
+		scope += a.Key + "=" + a.Value.String() + "|"
+	}
+	return &DedupingHandler{next: h.next.WithAttrs(attrs), mu: h.mu, last: h.last, scope: scope}
+}
+
+// WithGroup implements the slog.Handler interface.
+func (h *DedupingHandler) WithGroup(name string) slog.Handler {
+	return &DedupingHandler{next: h.next.WithGroup(name), mu: h.mu, last: h.last, scope: h.scope + "group=" + name + "|"}
+}
+
+// fingerprint renders the parts of r that make a log line distinct, so two
+// records with the same fingerprint are indistinguishable to a reader.
+func fingerprint(r slog.Record) string {
+	var b strings.Builder
+	b.WriteString(r.Level.String())
+	b.WriteByte('|')
+	b.WriteString(r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		b.WriteByte('|')
+		b.WriteString(a.Key)
+		b.WriteByte('=')
+		b.WriteString(a.Value.String())
+		return true
+	})
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// countingHandler counts how many records reach it, ignoring their content.
+type countingHandler struct {
+	count int
+}
+
+func (h *countingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *countingHandler) Handle(context.Context, slog.Record) error {
+	h.count++
+	return nil
+}
+
+func (h *countingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *countingHandler) WithGroup(string) slog.Handler      { return h }
+
+func record(msg string, attrs ...slog.Attr) slog.Record {
+	r := slog.NewRecord(time.Time{}, slog.LevelInfo, msg, 0)
+	r.AddAttrs(attrs...)
+	return r
+}
+
+func TestDedupingHandlerDropsConsecutiveDuplicates(t *testing.T) {
+	counting := &countingHandler{}
+	h := NewDedupingHandler(counting)
+
+	r := record("issue is unresolved, nothing to do", slog.String("key", "OPS-1"))
+	for i := 0; i < 5; i++ {
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+	if counting.count != 1 {
+		t.Fatalf("got %d records through, want 1 (later identical lines should be dropped)", counting.count)
+	}
+}
+
+func TestDedupingHandlerPassesThroughDistinctLines(t *testing.T) {
+	counting := &countingHandler{}
+	h := NewDedupingHandler(counting)
+
+	records := []slog.Record{
+		record("issue is unresolved, nothing to do", slog.String("key", "OPS-1")),
+		record("issue is unresolved, nothing to do", slog.String("key", "OPS-2")),
+		record("issue created", slog.String("key", "OPS-2")),
+	}
+	for _, r := range records {
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+	if counting.count != len(records) {
+		t.Fatalf("got %d records through, want %d (each differs from its predecessor)", counting.count, len(records))
+	}
+}
+
+func TestDedupingHandlerResumesAfterDifferentLine(t *testing.T) {
+	counting := &countingHandler{}
+	h := NewDedupingHandler(counting)
+
+	a := record("issue is unresolved, nothing to do", slog.String("key", "OPS-1"))
+	b := record("issue created", slog.String("key", "OPS-2"))
+
+	for _, r := range []slog.Record{a, a, b, a} {
+		if err := h.Handle(context.Background(), r); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+	}
+	if counting.count != 3 {
+		t.Fatalf("got %d records through, want 3 (second a is a duplicate of the first, but the final a follows b and should pass)", counting.count)
+	}
+}
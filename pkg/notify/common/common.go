@@ -0,0 +1,191 @@
+// Package common holds the pieces shared by every notify backend: the
+// Notifier interface dispatched on by cmd/jiralert, and the alert-group
+// bookkeeping (group IDs, labels, filters, retry classification) that is
+// identical no matter which issue tracker a receiver talks to.
+package common
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/free/jiralert/pkg/alertmanager"
+	"github.com/free/jiralert/pkg/config"
+)
+
+// Notifier turns one Alertmanager webhook payload into an action against an
+// issue tracker. The bool return reports whether the caller should retry the
+// notification on error (e.g. the tracker returned a transient 5xx).
+type Notifier interface {
+	Notify(data *alertmanager.Data, logger *slog.Logger) (bool, error)
+}
+
+// ToGroupID returns the group labels in the form of an ALERT metric name, with all spaces removed.
+func ToGroupID(groupLabels alertmanager.KV) string {
+	buf := bytes.NewBufferString("ALERT{")
+	for _, p := range groupLabels.SortedPairs() {
+		buf.WriteString(p.Name)
+		buf.WriteString(fmt.Sprintf("=%q,", p.Value))
+	}
+	buf.Truncate(buf.Len() - 1)
+	buf.WriteString("}")
+	return strings.Replace(buf.String(), " ", "", -1)
+}
+
+// ToGroupLabel renders groupID into a single opaque issue label, safe to
+// pass as-is to trackers (GitHub, GitLab, Gitea) whose search APIs treat a
+// label query parameter as a comma-separated list: groupID itself is an
+// ALERT{...} string full of commas and quotes, so using it as a label
+// verbatim would get split into several bogus label names on search.
+func ToGroupLabel(groupID string) string {
+	sum := sha1.Sum([]byte(groupID))
+	return fmt.Sprintf("jiralert-group:%x", sum)
+}
+
+// ToIssueLabel extracts the one group label field that we want to use as the issue label.
+func ToIssueLabel(labelKey string, groupLabels alertmanager.KV) (string, error) {
+	for _, p := range groupLabels.SortedPairs() {
+		if p.Name == labelKey {
+			return p.Value, nil
+		}
+	}
+	return "", errors.New("label key not found")
+}
+
+// IsResolvedNotification reports whether this notification means the alert
+// group has cleared: either Alertmanager marked the whole group resolved, or
+// every alert in it is (e.g. a partial re-notify that dropped to zero firing).
+func IsResolvedNotification(data *alertmanager.Data) bool {
+	return data.Status == "resolved" || len(data.Alerts.Firing()) == 0
+}
+
+// ShouldReopen reports whether an issue resolved at resolutionTime is still
+// within reopenDuration, and should therefore be reopened rather than
+// recreated from scratch.
+func ShouldReopen(resolutionTime time.Time, reopenDuration time.Duration) bool {
+	return resolutionTime.Add(reopenDuration).After(time.Now())
+}
+
+// ClassifyRetry reports whether a failed tracker API call is worth retrying,
+// based on its HTTP status code: only 500 and 503 are treated as transient;
+// every other status, including the remaining 5xx codes, is not retried.
+func ClassifyRetry(statusCode int) bool {
+	return statusCode == 500 || statusCode == 503
+}
+
+// Priority returns the issue priority for this notification, preferring a
+// SeverityPriorityMap match on the "severity" group label over the
+// receiver's static Priority.
+func Priority(conf *config.ReceiverConfig, data *alertmanager.Data) string {
+	if len(conf.SeverityPriorityMap) > 0 {
+		if severity, ok := data.GroupLabels["severity"]; ok {
+			if p, ok := conf.SeverityPriorityMap[severity]; ok {
+				return p
+			}
+		}
+	}
+	return conf.Priority
+}
+
+// ShouldSkipAssignee reports whether an assigned issue should be left alone
+// during auto-resolve: hasAssignee is a proxy for "a human is already
+// handling this and may not want it auto-closed", unless the receiver's
+// ResolveIgnoreAssignee explicitly opts back into resolving those too.
+func ShouldSkipAssignee(conf *config.ReceiverConfig, hasAssignee bool) bool {
+	return hasAssignee && !conf.ResolveIgnoreAssignee
+}
+
+// FirstMatchingFilter returns the first rule in conf.Filters whose match
+// criteria are satisfied by data, checking GroupLabels/CommonAnnotations and
+// falling back to each individual alert's labels/annotations. It returns nil
+// if no rule matches, in which case the alert group is allowed through.
+func FirstMatchingFilter(conf *config.ReceiverConfig, data *alertmanager.Data) *config.FilterRule {
+	for i, rule := range conf.Filters {
+		if FilterRuleMatches(rule, data.GroupLabels, data.CommonAnnotations) {
+			return &conf.Filters[i]
+		}
+		for _, alert := range data.Alerts {
+			if FilterRuleMatches(rule, alert.Labels, alert.Annotations) {
+				return &conf.Filters[i]
+			}
+		}
+	}
+	return nil
+}
+
+// FilterRuleMatches reports whether labels and annotations satisfy every
+// match criterion configured on rule. A rule with no criteria at all never
+// matches.
+func FilterRuleMatches(rule config.FilterRule, labels, annotations alertmanager.KV) bool {
+	if len(rule.Match) == 0 && len(rule.MatchRE) == 0 && len(rule.MatchAnnotations) == 0 &&
+		len(rule.MatchAnnotationsRE) == 0 && len(rule.SeverityIn) == 0 {
+		return false
+	}
+	for name, value := range rule.Match {
+		if labels[name] != value {
+			return false
+		}
+	}
+	for name, re := range rule.MatchRE {
+		if !re.MatchString(labels[name]) {
+			return false
+		}
+	}
+	for name, value := range rule.MatchAnnotations {
+		if annotations[name] != value {
+			return false
+		}
+	}
+	for name, re := range rule.MatchAnnotationsRE {
+		if !re.MatchString(annotations[name]) {
+			return false
+		}
+	}
+	if len(rule.SeverityIn) > 0 {
+		severity, ok := labels["severity"]
+		found := false
+		for _, s := range rule.SeverityIn {
+			if ok && s == severity {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// commentHashMarkerPrefix/Suffix bracket the content hash embedded in
+// templated comments, so LastCommentMarker can recognize one in free text.
+const (
+	commentHashMarkerPrefix = "<!-- jiralert:comment-hash:"
+	commentHashMarkerSuffix = "-->"
+)
+
+// CommentHashMarker renders an HTML-comment marker carrying a content hash
+// of body, appended to templated comments so a later notification can tell
+// whether the content actually changed before posting a new one.
+func CommentHashMarker(body string) string {
+	sum := sha1.Sum([]byte(body))
+	return fmt.Sprintf("%s%x %s", commentHashMarkerPrefix, sum[:8], commentHashMarkerSuffix)
+}
+
+// LastCommentMarker extracts the CommentHashMarker embedded in a comment
+// body, or "" if it has none.
+func LastCommentMarker(body string) string {
+	i := strings.Index(body, commentHashMarkerPrefix)
+	if i < 0 {
+		return ""
+	}
+	j := strings.Index(body[i:], commentHashMarkerSuffix)
+	if j < 0 {
+		return ""
+	}
+	return body[i : i+j+len(commentHashMarkerSuffix)]
+}
@@ -0,0 +1,239 @@
+package common
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/free/jiralert/pkg/alertmanager"
+	"github.com/free/jiralert/pkg/config"
+)
+
+func mustRegexp(pattern string) config.Regexp {
+	return config.Regexp{Regexp: regexp.MustCompile("^(?:" + pattern + ")$")}
+}
+
+func TestFilterRuleMatches(t *testing.T) {
+	cases := []struct {
+		name        string
+		rule        config.FilterRule
+		labels      alertmanager.KV
+		annotations alertmanager.KV
+		want        bool
+	}{
+		{
+			name:   "empty rule never matches",
+			rule:   config.FilterRule{},
+			labels: alertmanager.KV{"severity": "critical"},
+			want:   false,
+		},
+		{
+			name:   "match hits on exact label value",
+			rule:   config.FilterRule{Match: map[string]string{"team": "payments"}},
+			labels: alertmanager.KV{"team": "payments"},
+			want:   true,
+		},
+		{
+			name:   "match misses on different label value",
+			rule:   config.FilterRule{Match: map[string]string{"team": "payments"}},
+			labels: alertmanager.KV{"team": "infra"},
+			want:   false,
+		},
+		{
+			name:   "match_re hits on regex",
+			rule:   config.FilterRule{MatchRE: map[string]config.Regexp{"alertname": mustRegexp("Watchdog|DeadMansSwitch")}},
+			labels: alertmanager.KV{"alertname": "DeadMansSwitch"},
+			want:   true,
+		},
+		{
+			name:   "match_re misses when nothing matches",
+			rule:   config.FilterRule{MatchRE: map[string]config.Regexp{"alertname": mustRegexp("Watchdog")}},
+			labels: alertmanager.KV{"alertname": "HighLatency"},
+			want:   false,
+		},
+		{
+			name:        "match_annotations hits on exact annotation value",
+			rule:        config.FilterRule{MatchAnnotations: map[string]string{"runbook": "payments-oncall"}},
+			annotations: alertmanager.KV{"runbook": "payments-oncall"},
+			want:        true,
+		},
+		{
+			name:        "match_annotations misses on different annotation value",
+			rule:        config.FilterRule{MatchAnnotations: map[string]string{"runbook": "payments-oncall"}},
+			annotations: alertmanager.KV{"runbook": "infra-oncall"},
+			want:        false,
+		},
+		{
+			name:        "match_annotations_re hits on regex",
+			rule:        config.FilterRule{MatchAnnotationsRE: map[string]config.Regexp{"summary": mustRegexp(".*disk space.*")}},
+			annotations: alertmanager.KV{"summary": "node is low on disk space"},
+			want:        true,
+		},
+		{
+			name:        "match_annotations_re misses when nothing matches",
+			rule:        config.FilterRule{MatchAnnotationsRE: map[string]config.Regexp{"summary": mustRegexp(".*disk space.*")}},
+			annotations: alertmanager.KV{"summary": "latency is elevated"},
+			want:        false,
+		},
+		{
+			name:   "severity_in hits",
+			rule:   config.FilterRule{SeverityIn: []string{"warning", "critical"}},
+			labels: alertmanager.KV{"severity": "warning"},
+			want:   true,
+		},
+		{
+			name:   "severity_in misses when label absent",
+			rule:   config.FilterRule{SeverityIn: []string{"warning", "critical"}},
+			labels: alertmanager.KV{},
+			want:   false,
+		},
+		{
+			name: "all configured criteria must hold",
+			rule: config.FilterRule{
+				Match:      map[string]string{"team": "payments"},
+				SeverityIn: []string{"critical"},
+			},
+			labels: alertmanager.KV{"team": "payments", "severity": "warning"},
+			want:   false,
+		},
+		{
+			name: "label and annotation criteria must both hold",
+			rule: config.FilterRule{
+				Match:            map[string]string{"team": "payments"},
+				MatchAnnotations: map[string]string{"runbook": "payments-oncall"},
+			},
+			labels:      alertmanager.KV{"team": "payments"},
+			annotations: alertmanager.KV{"runbook": "infra-oncall"},
+			want:        false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := FilterRuleMatches(c.rule, c.labels, c.annotations); got != c.want {
+				t.Errorf("FilterRuleMatches() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestFirstMatchingFilter(t *testing.T) {
+	dropNoisy := config.FilterRule{Match: map[string]string{"team": "noisy"}, Action: config.FilterActionDrop}
+	allowPayments := config.FilterRule{Match: map[string]string{"team": "payments"}, Action: config.FilterActionAllow}
+
+	t.Run("matches on group labels", func(t *testing.T) {
+		conf := &config.ReceiverConfig{Filters: []config.FilterRule{dropNoisy, allowPayments}}
+		data := &alertmanager.Data{GroupLabels: alertmanager.KV{"team": "noisy"}}
+
+		got := FirstMatchingFilter(conf, data)
+		if got == nil || got.Action != config.FilterActionDrop {
+			t.Fatalf("got %+v, want the drop rule", got)
+		}
+	})
+
+	t.Run("falls back to individual alerts", func(t *testing.T) {
+		conf := &config.ReceiverConfig{Filters: []config.FilterRule{dropNoisy}}
+		data := &alertmanager.Data{
+			GroupLabels: alertmanager.KV{"alertname": "Watchdog"},
+			Alerts: alertmanager.Alerts{
+				{Labels: alertmanager.KV{"team": "infra"}},
+				{Labels: alertmanager.KV{"team": "noisy"}},
+			},
+		}
+
+		got := FirstMatchingFilter(conf, data)
+		if got == nil || got.Action != config.FilterActionDrop {
+			t.Fatalf("got %+v, want the drop rule to match the second alert", got)
+		}
+	})
+
+	t.Run("returns nil when nothing matches", func(t *testing.T) {
+		conf := &config.ReceiverConfig{Filters: []config.FilterRule{dropNoisy}}
+		data := &alertmanager.Data{GroupLabels: alertmanager.KV{"team": "payments"}}
+
+		if got := FirstMatchingFilter(conf, data); got != nil {
+			t.Fatalf("got %+v, want nil", got)
+		}
+	})
+
+	t.Run("evaluates rules in order", func(t *testing.T) {
+		conf := &config.ReceiverConfig{Filters: []config.FilterRule{allowPayments, dropNoisy}}
+		data := &alertmanager.Data{GroupLabels: alertmanager.KV{"team": "payments"}}
+
+		got := FirstMatchingFilter(conf, data)
+		if got == nil || got.Action != config.FilterActionAllow {
+			t.Fatalf("got %+v, want the first matching rule (allow)", got)
+		}
+	})
+}
+
+func TestCommentHashMarkerRoundTrip(t *testing.T) {
+	marker := CommentHashMarker("new alerts joined the group")
+	body := "new alerts joined the group\n" + marker
+
+	if got := LastCommentMarker(body); got != marker {
+		t.Errorf("LastCommentMarker() = %q, want %q", got, marker)
+	}
+}
+
+func TestCommentHashMarkerDiffersOnContentChange(t *testing.T) {
+	a := CommentHashMarker("alert group still firing")
+	b := CommentHashMarker("alert group still firing, now with one more alert")
+
+	if a == b {
+		t.Error("CommentHashMarker produced the same marker for different bodies")
+	}
+}
+
+func TestLastCommentMarkerNoMarker(t *testing.T) {
+	if got := LastCommentMarker("just a plain comment, no marker here"); got != "" {
+		t.Errorf("LastCommentMarker() = %q, want empty string", got)
+	}
+}
+
+func TestPriority(t *testing.T) {
+	t.Run("uses severity map when present", func(t *testing.T) {
+		conf := &config.ReceiverConfig{
+			Priority:            "Medium",
+			SeverityPriorityMap: map[string]string{"critical": "Highest"},
+		}
+		data := &alertmanager.Data{GroupLabels: alertmanager.KV{"severity": "critical"}}
+
+		if got := Priority(conf, data); got != "Highest" {
+			t.Errorf("Priority() = %q, want %q", got, "Highest")
+		}
+	})
+
+	t.Run("falls back to static priority when severity unmapped", func(t *testing.T) {
+		conf := &config.ReceiverConfig{
+			Priority:            "Medium",
+			SeverityPriorityMap: map[string]string{"critical": "Highest"},
+		}
+		data := &alertmanager.Data{GroupLabels: alertmanager.KV{"severity": "info"}}
+
+		if got := Priority(conf, data); got != "Medium" {
+			t.Errorf("Priority() = %q, want %q", got, "Medium")
+		}
+	})
+}
+
+func TestShouldSkipAssignee(t *testing.T) {
+	cases := []struct {
+		name                  string
+		resolveIgnoreAssignee bool
+		hasAssignee           bool
+		want                  bool
+	}{
+		{"unassigned issue is never skipped", false, false, false},
+		{"assigned issue is skipped by default", false, true, true},
+		{"assigned issue is not skipped when ResolveIgnoreAssignee is set", true, true, false},
+		{"unassigned issue is not skipped even with ResolveIgnoreAssignee set", true, false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			conf := &config.ReceiverConfig{ResolveIgnoreAssignee: c.resolveIgnoreAssignee}
+			if got := ShouldSkipAssignee(conf, c.hasAssignee); got != c.want {
+				t.Errorf("ShouldSkipAssignee() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,266 @@
+// Package gitea implements a notify.common.Notifier backed by a Gitea
+// repository's issue tracker, talking directly to the Gitea REST API (which
+// closely mirrors GitHub's, modulo path prefix and state naming).
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/free/jiralert/pkg/alertmanager"
+	"github.com/free/jiralert/pkg/config"
+	"github.com/free/jiralert/pkg/notify/common"
+	"github.com/free/jiralert/pkg/template"
+)
+
+// Notifier wraps a Gitea repository corresponding to a specific Alertmanager receiver, with its configuration and templates.
+type Notifier struct {
+	conf    *config.ReceiverConfig
+	tmpl    *template.Template
+	client  *http.Client
+	baseURL string // e.g. https://gitea.example.com/api/v1
+	repo    string // "owner/repo", taken from conf.Project
+}
+
+// New creates a Notifier using the provided configuration and template. It implements common.Notifier.
+func New(c *config.ReceiverConfig, t *template.Template) (*Notifier, error) {
+	token, err := c.Auth.PersonalAccessToken.Resolve(c.Auth.PersonalAccessTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("gitea: resolving auth.personal_access_token: %w", err)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("gitea: auth.personal_access_token must be set")
+	}
+	if c.APIURL == "" {
+		return nil, fmt.Errorf("gitea: api_url must be set")
+	}
+	return &Notifier{
+		conf:    c,
+		tmpl:    t,
+		client:  &http.Client{Transport: &tokenTransport{token: token}},
+		baseURL: c.APIURL,
+		repo:    c.Project,
+	}, nil
+}
+
+// tokenTransport injects a Gitea access token into every request.
+type tokenTransport struct {
+	token string
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("Authorization", "token "+t.token)
+	return http.DefaultTransport.RoundTrip(req2)
+}
+
+type giteaIssue struct {
+	Number    int        `json:"number"`
+	State     string     `json:"state"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	Assignees []struct {
+		Login string `json:"login"`
+	} `json:"assignees"`
+}
+
+// Notify implements the common.Notifier interface.
+func (n *Notifier) Notify(data *alertmanager.Data, logger *slog.Logger) (bool, error) {
+	if rule := common.FirstMatchingFilter(n.conf, data); rule != nil && rule.Action == config.FilterActionDrop {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "alert group dropped by filter")
+		return false, nil
+	}
+
+	groupID := common.ToGroupID(data.GroupLabels)
+	groupLabel := common.ToGroupLabel(groupID)
+	iss, retry, err := n.search(groupLabel, logger)
+	if err != nil {
+		return retry, err
+	}
+
+	if common.IsResolvedNotification(data) {
+		if !n.conf.AutoResolve || iss == nil || iss.State == "closed" {
+			return false, nil
+		}
+		if common.ShouldSkipAssignee(n.conf, len(iss.Assignees) > 0) {
+			logger.LogAttrs(context.Background(), slog.LevelInfo, "alert group resolved, but issue is assigned, not auto-resolving", slog.Int("number", iss.Number))
+			return false, nil
+		}
+		return n.setState(iss.Number, "closed", n.conf.ResolveComment, data, logger)
+	}
+
+	if iss != nil {
+		if iss.State != "closed" {
+			if n.conf.UpdateInPlace && n.conf.CommentTemplate != "" {
+				return n.addCommentIfChanged(iss.Number, data, logger)
+			}
+			logger.LogAttrs(context.Background(), slog.LevelDebug, "issue is open, nothing to do", slog.Int("number", iss.Number))
+			return false, nil
+		}
+		if iss.ClosedAt != nil && n.conf.ReopenDuration != nil && common.ShouldReopen(*iss.ClosedAt, time.Duration(*n.conf.ReopenDuration)) {
+			logger.LogAttrs(context.Background(), slog.LevelInfo, "issue was recently closed, reopening", slog.Int("number", iss.Number))
+			return n.setState(iss.Number, "open", "", data, logger)
+		}
+	}
+
+	title := n.tmpl.Execute(n.conf.Summary, data, logger)
+	body := n.tmpl.Execute(n.conf.Description, data, logger)
+	if err := n.tmpl.Err(); err != nil {
+		return false, err
+	}
+	payload := map[string]interface{}{
+		"title":  title,
+		"body":   body,
+		"labels": append([]string{groupLabel}, n.conf.Components...),
+	}
+	resp, err := n.do(http.MethodPost, fmt.Sprintf("/repos/%s/issues", n.repo), payload)
+	if err != nil {
+		return n.handleError("create issue", resp, err, logger)
+	}
+	resp.Body.Close()
+	logger.LogAttrs(context.Background(), slog.LevelInfo, "issue created", slog.String("group_id", groupID))
+	return false, nil
+}
+
+func (n *Notifier) search(groupLabel string, logger *slog.Logger) (*giteaIssue, bool, error) {
+	path := fmt.Sprintf("/repos/%s/issues?labels=%s&state=all&sort=recentupdate&limit=1", n.repo, url.QueryEscape(groupLabel))
+	resp, err := n.do(http.MethodGet, path, nil)
+	if err != nil {
+		retry, err := n.handleError("search issues", resp, err, logger)
+		return nil, retry, err
+	}
+	defer resp.Body.Close()
+	var issues []giteaIssue
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, false, fmt.Errorf("gitea: decoding search response: %w", err)
+	}
+	if len(issues) == 0 {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "no results", slog.String("label", groupLabel))
+		return nil, false, nil
+	}
+	return &issues[0], false, nil
+}
+
+func (n *Notifier) setState(number int, state, commentTemplate string, data *alertmanager.Data, logger *slog.Logger) (bool, error) {
+	resp, err := n.do(http.MethodPatch, fmt.Sprintf("/repos/%s/issues/%d", n.repo, number), map[string]interface{}{"state": state})
+	if err != nil {
+		return n.handleError("update issue state", resp, err, logger)
+	}
+	resp.Body.Close()
+	if commentTemplate != "" {
+		comment := n.tmpl.Execute(commentTemplate, data, logger)
+		if err := n.tmpl.Err(); err != nil {
+			return false, err
+		}
+		return n.addComment(number, comment, logger)
+	}
+	return false, nil
+}
+
+func (n *Notifier) addCommentIfChanged(number int, data *alertmanager.Data, logger *slog.Logger) (bool, error) {
+	body := n.tmpl.Execute(n.conf.CommentTemplate, data, logger)
+	if err := n.tmpl.Err(); err != nil {
+		return false, err
+	}
+	marker := common.CommentHashMarker(body)
+
+	last, retry, err := n.lastComment(number, logger)
+	if err != nil {
+		return retry, err
+	}
+	if last != "" && common.LastCommentMarker(last) == marker {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "comment unchanged since last notify, skipping", slog.Int("number", number))
+		return false, nil
+	}
+	return n.addComment(number, body+"\n"+marker, logger)
+}
+
+// lastComment returns the body of the most recently posted comment on
+// issue number, or "" if it has none. Gitea's issue-comments endpoint has
+// no sort/direction parameters the way its issues listing does - it always
+// returns oldest-first - so the only reliable way to find the true last
+// comment is to page through to the end rather than assume it fits on the
+// first (or any fixed-size) page.
+func (n *Notifier) lastComment(number int, logger *slog.Logger) (string, bool, error) {
+	const pageSize = 50
+	var last string
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?limit=%d&page=%d", n.repo, number, pageSize, page)
+		resp, err := n.do(http.MethodGet, path, nil)
+		if err != nil {
+			retry, err := n.handleError("list comments", resp, err, logger)
+			return "", retry, err
+		}
+		var comments []struct {
+			Body string `json:"body"`
+		}
+		decErr := json.NewDecoder(resp.Body).Decode(&comments)
+		resp.Body.Close()
+		if decErr != nil {
+			return "", false, fmt.Errorf("gitea: decoding comments response: %w", decErr)
+		}
+		if len(comments) == 0 {
+			return last, false, nil
+		}
+		last = comments[len(comments)-1].Body
+		if len(comments) < pageSize {
+			return last, false, nil
+		}
+	}
+}
+
+func (n *Notifier) addComment(number int, body string, logger *slog.Logger) (bool, error) {
+	resp, err := n.do(http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", n.repo, number), map[string]interface{}{"body": body})
+	if err != nil {
+		return n.handleError("add comment", resp, err, logger)
+	}
+	resp.Body.Close()
+	return false, nil
+}
+
+func (n *Notifier) do(method, path string, payload interface{}) (*http.Response, error) {
+	var body *bytes.Buffer
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewBuffer(b)
+	} else {
+		body = bytes.NewBuffer(nil)
+	}
+	req, err := http.NewRequest(method, n.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return resp, fmt.Errorf("gitea: %s %s returned %s", method, path, resp.Status)
+	}
+	return resp, nil
+}
+
+func (n *Notifier) handleError(op string, resp *http.Response, err error, logger *slog.Logger) (bool, error) {
+	if resp == nil {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "gitea request failed", slog.String("op", op), slog.Any("err", err))
+		return false, fmt.Errorf("gitea: %s: %w", op, err)
+	}
+	defer resp.Body.Close()
+	logger.LogAttrs(context.Background(), slog.LevelDebug, "gitea request failed", slog.String("op", op), slog.Int("status", resp.StatusCode))
+	msg := fmt.Sprintf("gitea: %s: status %s", op, resp.Status)
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		msg += " (check auth.personal_access_token)"
+	}
+	return common.ClassifyRetry(resp.StatusCode), errors.New(msg)
+}
@@ -0,0 +1,238 @@
+// Package gitlab implements a notify.common.Notifier backed by a GitLab
+// project's issue tracker, talking directly to the GitLab REST API.
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/free/jiralert/pkg/alertmanager"
+	"github.com/free/jiralert/pkg/config"
+	"github.com/free/jiralert/pkg/notify/common"
+	"github.com/free/jiralert/pkg/template"
+)
+
+// Notifier wraps a GitLab project corresponding to a specific Alertmanager receiver, with its configuration and templates.
+type Notifier struct {
+	conf    *config.ReceiverConfig
+	tmpl    *template.Template
+	client  *http.Client
+	baseURL string // e.g. https://gitlab.example.com/api/v4
+	project string // URL-encoded "namespace/project" path, i.e. Project ID
+}
+
+// New creates a Notifier using the provided configuration and template. It implements common.Notifier.
+func New(c *config.ReceiverConfig, t *template.Template) (*Notifier, error) {
+	token, err := c.Auth.PersonalAccessToken.Resolve(c.Auth.PersonalAccessTokenFile)
+	if err != nil {
+		return nil, fmt.Errorf("gitlab: resolving auth.personal_access_token: %w", err)
+	}
+	if token == "" {
+		return nil, fmt.Errorf("gitlab: auth.personal_access_token must be set")
+	}
+	return &Notifier{
+		conf:    c,
+		tmpl:    t,
+		client:  &http.Client{Transport: &tokenTransport{token: token}},
+		baseURL: c.APIURL,
+		project: url.PathEscape(c.Project),
+	}, nil
+}
+
+// tokenTransport injects a GitLab personal/project access token into every request.
+type tokenTransport struct {
+	token string
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := req.Clone(req.Context())
+	req2.Header.Set("PRIVATE-TOKEN", t.token)
+	return http.DefaultTransport.RoundTrip(req2)
+}
+
+type issue struct {
+	IID       int        `json:"iid"`
+	State     string     `json:"state"`
+	Labels    []string   `json:"labels"`
+	ClosedAt  *time.Time `json:"closed_at"`
+	Assignees []struct {
+		Username string `json:"username"`
+	} `json:"assignees"`
+}
+
+// Notify implements the common.Notifier interface.
+func (n *Notifier) Notify(data *alertmanager.Data, logger *slog.Logger) (bool, error) {
+	if rule := common.FirstMatchingFilter(n.conf, data); rule != nil && rule.Action == config.FilterActionDrop {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "alert group dropped by filter")
+		return false, nil
+	}
+
+	groupID := common.ToGroupID(data.GroupLabels)
+	groupLabel := common.ToGroupLabel(groupID)
+	iss, retry, err := n.search(groupLabel, logger)
+	if err != nil {
+		return retry, err
+	}
+
+	if common.IsResolvedNotification(data) {
+		if !n.conf.AutoResolve || iss == nil || iss.State == "closed" {
+			return false, nil
+		}
+		if common.ShouldSkipAssignee(n.conf, len(iss.Assignees) > 0) {
+			logger.LogAttrs(context.Background(), slog.LevelInfo, "alert group resolved, but issue is assigned, not auto-resolving", slog.Int("iid", iss.IID))
+			return false, nil
+		}
+		return n.setState(iss.IID, "close", n.conf.ResolveComment, data, logger)
+	}
+
+	if iss != nil {
+		if iss.State != "closed" {
+			if n.conf.UpdateInPlace && n.conf.CommentTemplate != "" {
+				return n.addCommentIfChanged(iss.IID, data, logger)
+			}
+			logger.LogAttrs(context.Background(), slog.LevelDebug, "issue is open, nothing to do", slog.Int("iid", iss.IID))
+			return false, nil
+		}
+		if iss.ClosedAt != nil && n.conf.ReopenDuration != nil && common.ShouldReopen(*iss.ClosedAt, time.Duration(*n.conf.ReopenDuration)) {
+			logger.LogAttrs(context.Background(), slog.LevelInfo, "issue was recently closed, reopening", slog.Int("iid", iss.IID))
+			return n.setState(iss.IID, "reopen", "", data, logger)
+		}
+	}
+
+	title := n.tmpl.Execute(n.conf.Summary, data, logger)
+	body := n.tmpl.Execute(n.conf.Description, data, logger)
+	if err := n.tmpl.Err(); err != nil {
+		return false, err
+	}
+	labels := append([]string{groupLabel}, n.conf.Components...)
+	payload := map[string]interface{}{
+		"title":       title,
+		"description": body,
+		"labels":      labels,
+	}
+	resp, err := n.do(http.MethodPost, fmt.Sprintf("/projects/%s/issues", n.project), payload)
+	if err != nil {
+		return n.handleError("create issue", resp, err, logger)
+	}
+	resp.Body.Close()
+	logger.LogAttrs(context.Background(), slog.LevelInfo, "issue created", slog.String("group_id", groupID))
+	return false, nil
+}
+
+func (n *Notifier) search(groupLabel string, logger *slog.Logger) (*issue, bool, error) {
+	path := fmt.Sprintf("/projects/%s/issues?labels=%s&order_by=updated_at&per_page=1", n.project, url.QueryEscape(groupLabel))
+	resp, err := n.do(http.MethodGet, path, nil)
+	if err != nil {
+		retry, err := n.handleError("search issues", resp, err, logger)
+		return nil, retry, err
+	}
+	defer resp.Body.Close()
+	var issues []issue
+	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
+		return nil, false, fmt.Errorf("gitlab: decoding search response: %w", err)
+	}
+	if len(issues) == 0 {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "no results", slog.String("label", groupLabel))
+		return nil, false, nil
+	}
+	return &issues[0], false, nil
+}
+
+func (n *Notifier) setState(iid int, stateEvent, commentTemplate string, data *alertmanager.Data, logger *slog.Logger) (bool, error) {
+	resp, err := n.do(http.MethodPut, fmt.Sprintf("/projects/%s/issues/%d", n.project, iid), map[string]interface{}{"state_event": stateEvent})
+	if err != nil {
+		return n.handleError("update issue state", resp, err, logger)
+	}
+	resp.Body.Close()
+	if commentTemplate != "" {
+		comment := n.tmpl.Execute(commentTemplate, data, logger)
+		if err := n.tmpl.Err(); err != nil {
+			return false, err
+		}
+		return n.addComment(iid, comment, logger)
+	}
+	return false, nil
+}
+
+func (n *Notifier) addCommentIfChanged(iid int, data *alertmanager.Data, logger *slog.Logger) (bool, error) {
+	body := n.tmpl.Execute(n.conf.CommentTemplate, data, logger)
+	if err := n.tmpl.Err(); err != nil {
+		return false, err
+	}
+	marker := common.CommentHashMarker(body)
+
+	path := fmt.Sprintf("/projects/%s/issues/%d/notes?order_by=created_at&sort=desc&per_page=1", n.project, iid)
+	resp, err := n.do(http.MethodGet, path, nil)
+	if err != nil {
+		return n.handleError("list notes", resp, err, logger)
+	}
+	defer resp.Body.Close()
+	var notes []struct {
+		Body string `json:"body"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&notes); err != nil {
+		return false, fmt.Errorf("gitlab: decoding notes response: %w", err)
+	}
+	if len(notes) > 0 && common.LastCommentMarker(notes[0].Body) == marker {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "comment unchanged since last notify, skipping", slog.Int("iid", iid))
+		return false, nil
+	}
+	return n.addComment(iid, body+"\n"+marker, logger)
+}
+
+func (n *Notifier) addComment(iid int, body string, logger *slog.Logger) (bool, error) {
+	resp, err := n.do(http.MethodPost, fmt.Sprintf("/projects/%s/issues/%d/notes", n.project, iid), map[string]interface{}{"body": body})
+	if err != nil {
+		return n.handleError("add note", resp, err, logger)
+	}
+	resp.Body.Close()
+	return false, nil
+}
+
+func (n *Notifier) do(method, path string, payload interface{}) (*http.Response, error) {
+	var body *bytes.Buffer
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewBuffer(b)
+	} else {
+		body = bytes.NewBuffer(nil)
+	}
+	req, err := http.NewRequest(method, n.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return resp, fmt.Errorf("gitlab: %s %s returned %s", method, path, resp.Status)
+	}
+	return resp, nil
+}
+
+func (n *Notifier) handleError(op string, resp *http.Response, err error, logger *slog.Logger) (bool, error) {
+	if resp == nil {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "gitlab request failed", slog.String("op", op), slog.Any("err", err))
+		return false, fmt.Errorf("gitlab: %s: %w", op, err)
+	}
+	defer resp.Body.Close()
+	logger.LogAttrs(context.Background(), slog.LevelDebug, "gitlab request failed", slog.String("op", op), slog.Int("status", resp.StatusCode))
+	msg := fmt.Sprintf("gitlab: %s: status %s", op, resp.Status)
+	if resp.StatusCode == 401 || resp.StatusCode == 403 {
+		msg += " (check auth.personal_access_token)"
+	}
+	return common.ClassifyRetry(resp.StatusCode), errors.New(msg)
+}
@@ -0,0 +1,178 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/free/jiralert/pkg/alertmanager"
+	"github.com/free/jiralert/pkg/config"
+	"github.com/free/jiralert/pkg/notify/common"
+	"github.com/free/jiralert/pkg/template"
+)
+
+// TestSearchFindsCreatedIssue reproduces a multi-label group ID from
+// ToGroupID against a fake GitLab API and checks that search finds the
+// issue create just made. GitLab's issues endpoint treats a "labels" query
+// parameter as a comma-separated list, so a naive groupLabel built directly
+// from ToGroupID's commas and quotes would get split into several bogus
+// label names and search would always come back empty.
+func TestSearchFindsCreatedIssue(t *testing.T) {
+	groupLabels := alertmanager.KV{"alertname": "Watchdog", "severity": "critical, urgent"}
+	groupID := common.ToGroupID(groupLabels)
+	groupLabel := common.ToGroupLabel(groupID)
+
+	// ServeMux matches against the already-percent-decoded r.URL.Path, where
+	// "ns%2Fproj" has turned back into "ns/proj" — register a catch-all and
+	// check the raw (still-encoded) path ourselves instead of relying on
+	// mux pattern matching to see the literal "%2F".
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/projects/ns%2Fproj/issues" {
+			t.Fatalf("unexpected request path %q", r.URL.EscapedPath())
+		}
+		labelsParam, err := url.QueryUnescape(r.URL.Query().Get("labels"))
+		if err != nil {
+			t.Fatalf("unescaping labels param: %v", err)
+		}
+		if strings.Contains(labelsParam, ",") {
+			t.Fatalf("search label query must be a single literal label, got %q", labelsParam)
+		}
+		if labelsParam != groupLabel {
+			json.NewEncoder(w).Encode([]issue{})
+			return
+		}
+		json.NewEncoder(w).Encode([]issue{{IID: 7, State: "opened"}})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	n := &Notifier{client: srv.Client(), baseURL: srv.URL, project: "ns%2Fproj"}
+
+	iss, _, err := n.search(groupLabel, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	if iss == nil {
+		t.Fatal("search: expected to find the issue just created, got nil")
+	}
+	if iss.IID != 7 {
+		t.Fatalf("search: got iid %d, want 7", iss.IID)
+	}
+}
+
+// closeTrackingTransport wraps every response body so a test can assert it
+// was closed, even on the error branch where the caller never reads it.
+type closeTrackingTransport struct {
+	underlying http.RoundTripper
+	closed     *bool
+}
+
+func (t closeTrackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.underlying.RoundTrip(req)
+	if resp != nil {
+		resp.Body = closeTrackingBody{resp.Body, t.closed}
+	}
+	return resp, err
+}
+
+type closeTrackingBody struct {
+	io.ReadCloser
+	closed *bool
+}
+
+func (b closeTrackingBody) Close() error {
+	*b.closed = true
+	return b.ReadCloser.Close()
+}
+
+// TestSearchErrorClosesResponseBody guards against leaking the connection
+// on every failed search (e.g. a bad token returning 401) - the most likely
+// failure mode in production, and one that's easy to miss because the
+// success path's `defer resp.Body.Close()` only runs once search has
+// already returned past the error branch.
+func TestSearchErrorClosesResponseBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	var closed bool
+	client := &http.Client{Transport: closeTrackingTransport{underlying: http.DefaultTransport, closed: &closed}}
+	n := &Notifier{client: client, baseURL: srv.URL, project: "ns%2Fproj"}
+
+	_, _, err := n.search("jiralert-group:abc", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err == nil {
+		t.Fatal("search: expected an error for a 401 response")
+	}
+	if !closed {
+		t.Error("search: response body was not closed on the error path")
+	}
+}
+
+// loadTestTemplate builds a Template usable by Notify without a fixture
+// file on disk, for tests that only need Summary/Description rendered from
+// inline strings rather than named templates.
+func loadTestTemplate(t *testing.T) *template.Template {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jiralert.tmpl")
+	if err := os.WriteFile(path, []byte(""), 0600); err != nil {
+		t.Fatalf("writing template fixture: %v", err)
+	}
+	tmpl, err := template.LoadTemplate(path)
+	if err != nil {
+		t.Fatalf("loading template: %v", err)
+	}
+	return tmpl
+}
+
+// TestNotifySkipsReopenWhenReopenDurationUnset reproduces a receiver that
+// omits reopen_duration (it's optional, yaml:",omitempty") against a closed
+// issue: before the nil guard, time.Duration(*n.conf.ReopenDuration) paniced
+// on the very first re-fire of a group whose issue was already closed.
+func TestNotifySkipsReopenWhenReopenDurationUnset(t *testing.T) {
+	var created bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			closedAt := time.Now()
+			json.NewEncoder(w).Encode([]issue{{IID: 7, State: "closed", ClosedAt: &closedAt}})
+		case http.MethodPost:
+			created = true
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(issue{IID: 8, State: "opened"})
+		}
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	n := &Notifier{
+		conf:    &config.ReceiverConfig{Project: "ns/proj", Summary: "summary", Description: "description"},
+		tmpl:    loadTestTemplate(t),
+		client:  srv.Client(),
+		baseURL: srv.URL,
+		project: "ns%2Fproj",
+	}
+
+	data := &alertmanager.Data{
+		Status:      "firing",
+		Alerts:      alertmanager.Alerts{{Status: "firing"}},
+		GroupLabels: alertmanager.KV{"alertname": "Watchdog"},
+	}
+
+	if _, err := n.Notify(data, slog.New(slog.NewTextHandler(io.Discard, nil))); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if !created {
+		t.Error("Notify: expected a new issue to be created since reopen was skipped with ReopenDuration unset")
+	}
+}
@@ -0,0 +1,565 @@
+// Package jira implements a notify.common.Notifier backed by a JIRA project,
+// using the go-jira client.
+package jira
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/free/jiralert/pkg/config"
+	"github.com/free/jiralert/pkg/notify/common"
+	"github.com/free/jiralert/pkg/template"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/free/jiralert/pkg/alertmanager"
+	"github.com/trivago/tgo/tcontainer"
+)
+
+// Notifier wraps a JIRA client corresponding to a specific Alertmanager receiver, with its configuration and templates.
+type Notifier struct {
+	conf   *config.ReceiverConfig
+	tmpl   *template.Template
+	client *jira.Client
+}
+
+// New creates a Notifier using the provided configuration and template. It implements common.Notifier.
+func New(c *config.ReceiverConfig, t *template.Template) (*Notifier, error) {
+	httpClient, err := authClient(c)
+	if err != nil {
+		return nil, err
+	}
+	client, err := jira.NewClient(httpClient, c.APIURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Notifier{conf: c, tmpl: t, client: client}, nil
+}
+
+// authClient builds the *http.Client used by the JIRA client, selecting a
+// RoundTripper based on the receiver's AuthConfig.
+func authClient(c *config.ReceiverConfig) (*http.Client, error) {
+	auth := c.Auth
+	if auth.Mode == "" {
+		// Deprecated User/Password fields, kept for backwards compatibility.
+		auth = config.AuthConfig{Mode: config.AuthBasic, User: c.User, Password: c.Password}
+	}
+
+	switch auth.Mode {
+	case config.AuthBasic:
+		password, err := auth.Password.Resolve(auth.PasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth: resolving password: %w", err)
+		}
+		tp := jira.BasicAuthTransport{
+			Username: auth.User,
+			Password: password,
+		}
+		return tp.Client(), nil
+
+	case config.AuthPAT:
+		if auth.PersonalAccessToken == "" && auth.PersonalAccessTokenFile == "" {
+			return nil, errors.New("auth: personal_access_token or personal_access_token_file must be set when auth.mode is \"pat\"")
+		}
+		token, err := auth.PersonalAccessToken.Resolve(auth.PersonalAccessTokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("auth: resolving personal_access_token: %w", err)
+		}
+		return (&bearerTokenTransport{token: token}).Client(), nil
+
+	case config.AuthOAuth:
+		if auth.OAuthConsumerKey == "" || auth.OAuthPrivateKeyPath == "" {
+			return nil, errors.New("auth: oauth_consumer_key and oauth_private_key_path must both be set when auth.mode is \"oauth\"")
+		}
+		key, err := ioutil.ReadFile(auth.OAuthPrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("auth: reading oauth_private_key_path: %w", err)
+		}
+		return (&oauthJWTTransport{consumerKey: auth.OAuthConsumerKey, privateKeyPEM: key}).Client(), nil
+
+	default:
+		return nil, fmt.Errorf("auth: unknown mode %q", auth.Mode)
+	}
+}
+
+// bearerTokenTransport is a http.RoundTripper that injects a bearer Personal
+// Access Token into every request, for JIRA deployments that reject basic
+// auth and don't need the full OAuth1/JWT dance (e.g. Jira Data Center PATs
+// and Jira Cloud API tokens used without a go-jira-native transport).
+type bearerTokenTransport struct {
+	token     string
+	transport http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *bearerTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := cloneRequest(req)
+	req2.Header.Set("Authorization", "Bearer "+t.token)
+	return t.transport.RoundTrip(req2)
+}
+
+// Client returns an *http.Client that uses this transport to authenticate.
+func (t *bearerTokenTransport) Client() *http.Client {
+	if t.transport == nil {
+		t.transport = http.DefaultTransport
+	}
+	return &http.Client{Transport: t}
+}
+
+// cloneRequest returns a shallow copy of req with its own Header map, so
+// setting the Authorization header doesn't mutate the caller's request.
+func cloneRequest(req *http.Request) *http.Request {
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	return r
+}
+
+// oauthPercentEncode percent-encodes s per RFC 5849 section 3.6, which
+// mandates RFC 3986 unreserved-character encoding (unreserved: A-Z a-z 0-9
+// - . _ ~, space as %20) and explicitly forbids the
+// application/x-www-form-urlencoded convention (space as "+") that
+// url.QueryEscape implements.
+func oauthPercentEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// oauthJWTTransport signs every request as a two-legged OAuth1 request using
+// RSA-SHA1, the flow Jira Server/Data Center applications use for trusted
+// application links (no access token, no user interaction required).
+type oauthJWTTransport struct {
+	consumerKey   string
+	privateKeyPEM []byte
+	transport     http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *oauthJWTTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	block, _ := pem.Decode(t.privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("auth: oauth_private_key_path does not contain a PEM block")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("auth: parsing oauth private key: %w", err)
+	}
+
+	nonce := fmt.Sprintf("%d", time.Now().UnixNano())
+	timestamp := fmt.Sprintf("%d", time.Now().Unix())
+	params := map[string]string{
+		"oauth_consumer_key":     t.consumerKey,
+		"oauth_nonce":            nonce,
+		"oauth_signature_method": "RSA-SHA1",
+		"oauth_timestamp":        timestamp,
+		"oauth_version":          "1.0",
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// RFC 5849 section 3.4.1 requires the signature to cover every request
+	// parameter, not just the oauth_* ones: fold the request's query
+	// string (e.g. Issue.Search's jql=...) into the same normalized
+	// parameter set used for the base string.
+	baseURL := *req.URL
+	query := baseURL.Query()
+	baseURL.RawQuery = ""
+
+	type param struct{ key, value string }
+	signingParams := make([]param, 0, len(params)+len(query))
+	for k, v := range params {
+		signingParams = append(signingParams, param{oauthPercentEncode(k), oauthPercentEncode(v)})
+	}
+	for k, values := range query {
+		for _, v := range values {
+			signingParams = append(signingParams, param{oauthPercentEncode(k), oauthPercentEncode(v)})
+		}
+	}
+	sort.Slice(signingParams, func(i, j int) bool {
+		if signingParams[i].key != signingParams[j].key {
+			return signingParams[i].key < signingParams[j].key
+		}
+		return signingParams[i].value < signingParams[j].value
+	})
+	encoded := make([]string, len(signingParams))
+	for i, p := range signingParams {
+		encoded[i] = p.key + "=" + p.value
+	}
+	baseString := strings.Join([]string{req.Method, oauthPercentEncode(baseURL.String()), oauthPercentEncode(strings.Join(encoded, "&"))}, "&")
+
+	hashed := sha1.Sum([]byte(baseString))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, hashed[:])
+	if err != nil {
+		return nil, fmt.Errorf("auth: signing oauth request: %w", err)
+	}
+	params["oauth_signature"] = base64.StdEncoding.EncodeToString(signature)
+
+	var header strings.Builder
+	header.WriteString("OAuth ")
+	for i, k := range append(keys, "oauth_signature") {
+		if i > 0 {
+			header.WriteString(", ")
+		}
+		header.WriteString(fmt.Sprintf("%s=%q", k, oauthPercentEncode(params[k])))
+	}
+
+	req2 := cloneRequest(req)
+	req2.Header.Set("Authorization", header.String())
+	transport := t.transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(req2)
+}
+
+// Client returns an *http.Client that uses this transport to authenticate.
+func (t *oauthJWTTransport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Notify implements the common.Notifier interface.
+func (r *Notifier) Notify(data *alertmanager.Data, logger *slog.Logger) (bool, error) {
+	if rule := common.FirstMatchingFilter(r.conf, data); rule != nil && rule.Action == config.FilterActionDrop {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "alert group dropped by filter", slog.Any("match", rule.Match), slog.Any("match_re", rule.MatchRE), slog.Any("severity_in", rule.SeverityIn))
+		return false, nil
+	}
+
+	project := r.tmpl.Execute(r.conf.Project, data, logger)
+	if err := r.tmpl.Err(); err != nil {
+		return false, err
+	}
+	groupID := common.ToGroupID(data.GroupLabels)
+	issue, retry, err := r.search(project, groupID, logger)
+	if err != nil {
+		return retry, err
+	}
+
+	if common.IsResolvedNotification(data) {
+		if !r.conf.AutoResolve {
+			logger.LogAttrs(context.Background(), slog.LevelDebug, "alert group resolved, auto_resolve disabled, nothing to do", slog.String("label", groupID))
+			return false, nil
+		}
+		return r.resolve(issue, data, groupID, logger)
+	}
+
+	issueLabel, err := common.ToIssueLabel(r.conf.LabelKey, data.GroupLabels)
+	if err != nil {
+		logger.LogAttrs(context.Background(), slog.LevelWarn, err.Error())
+	}
+
+	if issue != nil {
+		// The set of JIRA status categories is fixed, this is a safe check to make.
+		if issue.Fields.Status.StatusCategory.Key != "done" {
+			// Issue is in a "to do" or "in progress" state.
+			if r.conf.UpdateInPlace {
+				return r.updateInPlace(issue, data, groupID, logger)
+			}
+			logger.LogAttrs(context.Background(), slog.LevelDebug, "issue is unresolved, nothing to do", slog.String("key", issue.Key), slog.String("label", groupID))
+			return false, nil
+		}
+		if r.conf.WontFixResolution != "" && issue.Fields.Resolution != nil &&
+			issue.Fields.Resolution.Name == r.conf.WontFixResolution {
+			// Issue is resolved as "Won't Fix" or equivalent, log a message just in case.
+			logger.LogAttrs(context.Background(), slog.LevelInfo, "issue was resolved as won't fix, not reopening", slog.String("key", issue.Key), slog.String("label", groupID), slog.String("resolution", issue.Fields.Resolution.Name))
+			return false, nil
+		}
+
+		resolutionTime := time.Time(issue.Fields.Resolutiondate)
+		if r.conf.ReopenDuration != nil && common.ShouldReopen(resolutionTime, time.Duration(*r.conf.ReopenDuration)) {
+			logger.LogAttrs(context.Background(), slog.LevelInfo, "issue was recently resolved, reopening", slog.String("key", issue.Key), slog.String("label", groupID), slog.String("resolution_time", resolutionTime.Format(time.RFC3339)), slog.Duration("reopen_duration", time.Duration(*r.conf.ReopenDuration)))
+			return r.reopen(issue.Key, logger)
+		}
+	}
+
+	logger.LogAttrs(context.Background(), slog.LevelInfo, "no recent matching issue found, creating new issue", slog.String("label", groupID))
+	customFields := tcontainer.NewMarshalMap()
+	customFields[r.conf.GroupFieldID] = []string{
+		groupID,
+	}
+
+	issue = &jira.Issue{
+		Fields: &jira.IssueFields{
+			Project:     jira.Project{Key: project},
+			Type:        jira.IssueType{Name: r.tmpl.Execute(r.conf.IssueType, data, logger)},
+			Description: r.tmpl.Execute(r.conf.Description, data, logger),
+			Summary:     r.tmpl.Execute(r.conf.Summary, data, logger),
+			Labels: []string{
+				issueLabel,
+			},
+			Unknowns: customFields,
+		},
+	}
+	if priority := common.Priority(r.conf, data); priority != "" {
+		issue.Fields.Priority = &jira.Priority{Name: r.tmpl.Execute(priority, data, logger)}
+	}
+
+	// Add Components
+	if len(r.conf.Components) > 0 {
+		issue.Fields.Components = make([]*jira.Component, 0, len(r.conf.Components))
+		for _, component := range r.conf.Components {
+			issue.Fields.Components = append(issue.Fields.Components, &jira.Component{Name: r.tmpl.Execute(component, data, logger)})
+		}
+	}
+
+	// Add Labels
+	if r.conf.AddGroupLabels {
+		for k, v := range data.GroupLabels {
+			issue.Fields.Labels = append(issue.Fields.Labels, fmt.Sprintf("%s=%q", k, v))
+		}
+	}
+
+	if err := r.tmpl.Err(); err != nil {
+		return false, err
+	}
+	retry, err = r.create(issue, logger)
+	if err == nil {
+		logger.LogAttrs(context.Background(), slog.LevelInfo, "issue created", slog.String("key", issue.Key), slog.String("id", issue.ID))
+	}
+	return retry, err
+}
+
+func (r *Notifier) search(project, groupID string, logger *slog.Logger) (*jira.Issue, bool, error) {
+	query := fmt.Sprintf("project=\"%s\" and %q=%q order by resolutiondate desc", project, r.conf.GroupFieldName, groupID)
+	options := &jira.SearchOptions{
+		Fields:     []string{"summary", "status", "resolution", "resolutiondate", "assignee", "comment"},
+		MaxResults: 2,
+	}
+	logger.LogAttrs(context.Background(), slog.LevelDebug, "search", slog.String("query", query), slog.Any("options", options))
+	issues, resp, err := r.client.Issue.Search(query, options)
+	if err != nil {
+		retry, err := r.handleJiraError("Issue.Search", resp, err, logger)
+		return nil, retry, err
+	}
+	if len(issues) > 0 {
+		if len(issues) > 1 {
+			// Swallow it, but log a message.
+			logger.LogAttrs(context.Background(), slog.LevelDebug, "more than one issue matched, picking most recently resolved", slog.String("query", query), slog.Any("issues", issues))
+		}
+
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "found", slog.Any("issue", issues[0]), slog.String("query", query))
+		return &issues[0], false, nil
+	}
+	logger.LogAttrs(context.Background(), slog.LevelDebug, "no results", slog.String("query", query))
+	return nil, false, nil
+}
+
+func (r *Notifier) reopen(issueKey string, logger *slog.Logger) (bool, error) {
+	transitions, resp, err := r.client.Issue.GetTransitions(issueKey)
+	if err != nil {
+		return r.handleJiraError("Issue.GetTransitions", resp, err, logger)
+	}
+	for _, t := range transitions {
+		if t.Name == r.conf.ReopenState {
+			logger.LogAttrs(context.Background(), slog.LevelDebug, "reopen", slog.String("key", issueKey), slog.String("transitionID", t.ID))
+			resp, err = r.client.Issue.DoTransition(issueKey, t.ID)
+			if err != nil {
+				return r.handleJiraError("Issue.DoTransition", resp, err, logger)
+			}
+
+			logger.LogAttrs(context.Background(), slog.LevelDebug, "reopen done")
+			return false, nil
+		}
+	}
+	return false, fmt.Errorf("JIRA state %q does not exist or no transition possible for %s", r.conf.ReopenState, issueKey)
+}
+
+// resolve transitions issue to a "done" state via the configured
+// ResolveTransition, once the alert group that created it has cleared. It is
+// a no-op if there is no matching issue, the issue is already done, or the
+// issue has been assigned to someone (a proxy for "a human is already
+// handling this and may not want it auto-closed") - unless
+// ResolveIgnoreAssignee explicitly opts back into resolving those too.
+func (r *Notifier) resolve(issue *jira.Issue, data *alertmanager.Data, groupID string, logger *slog.Logger) (bool, error) {
+	if issue == nil {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "alert group resolved, no matching issue found, nothing to do", slog.String("label", groupID))
+		return false, nil
+	}
+	if issue.Fields.Status.StatusCategory.Key == "done" {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "alert group resolved, issue already done, nothing to do", slog.String("key", issue.Key), slog.String("label", groupID))
+		return false, nil
+	}
+	if common.ShouldSkipAssignee(r.conf, issue.Fields.Assignee != nil) {
+		logger.LogAttrs(context.Background(), slog.LevelInfo, "alert group resolved, but issue is assigned, not auto-resolving", slog.String("key", issue.Key), slog.String("label", groupID), slog.String("assignee", issue.Fields.Assignee.Name))
+		return false, nil
+	}
+	if r.conf.ResolveTransition == "" {
+		return false, fmt.Errorf("alert group resolved but no resolve_transition configured for issue %s", issue.Key)
+	}
+
+	transitions, resp, err := r.client.Issue.GetTransitions(issue.Key)
+	if err != nil {
+		return r.handleJiraError("Issue.GetTransitions", resp, err, logger)
+	}
+	for _, t := range transitions {
+		if t.Name != r.conf.ResolveTransition {
+			continue
+		}
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "resolve", slog.String("key", issue.Key), slog.String("transitionID", t.ID))
+		if resp, err := r.client.Issue.DoTransition(issue.Key, t.ID); err != nil {
+			return r.handleJiraError("Issue.DoTransition", resp, err, logger)
+		}
+
+		if r.conf.ResolveResolution != "" {
+			update := map[string]interface{}{"fields": map[string]interface{}{"resolution": map[string]interface{}{"name": r.conf.ResolveResolution}}}
+			if resp, err := r.client.Issue.UpdateIssue(issue.Key, update); err != nil {
+				return r.handleJiraError("Issue.UpdateIssue", resp, err, logger)
+			}
+		}
+
+		if r.conf.ResolveComment != "" {
+			comment := r.tmpl.Execute(r.conf.ResolveComment, data, logger)
+			if err := r.tmpl.Err(); err != nil {
+				return false, err
+			}
+			if _, resp, err := r.client.Issue.AddComment(issue.Key, &jira.Comment{Body: comment}); err != nil {
+				return r.handleJiraError("Issue.AddComment", resp, err, logger)
+			}
+		}
+
+		logger.LogAttrs(context.Background(), slog.LevelInfo, "issue resolved", slog.String("key", issue.Key), slog.String("label", groupID))
+		return false, nil
+	}
+	return false, fmt.Errorf("JIRA state %q does not exist or no transition possible for %s", r.conf.ResolveTransition, issue.Key)
+}
+
+// updateInPlace keeps an already-open issue current: it appends a templated
+// comment describing the latest notification (skipping it if the most
+// recent comment already carries an identical content hash, so repeated
+// Alertmanager re-notifies don't spam the ticket) and patches the
+// configured subset of fields.
+func (r *Notifier) updateInPlace(issue *jira.Issue, data *alertmanager.Data, groupID string, logger *slog.Logger) (bool, error) {
+	if r.conf.CommentTemplate != "" {
+		body := r.tmpl.Execute(r.conf.CommentTemplate, data, logger)
+		if err := r.tmpl.Err(); err != nil {
+			return false, err
+		}
+		marker := common.CommentHashMarker(body)
+
+		if lastCommentMarker(issue) == marker {
+			logger.LogAttrs(context.Background(), slog.LevelDebug, "issue unresolved, comment unchanged since last notify, skipping", slog.String("key", issue.Key), slog.String("label", groupID))
+		} else {
+			if _, resp, err := r.client.Issue.AddComment(issue.Key, &jira.Comment{Body: body + "\n" + marker}); err != nil {
+				return r.handleJiraError("Issue.AddComment", resp, err, logger)
+			}
+			logger.LogAttrs(context.Background(), slog.LevelInfo, "issue unresolved, added comment", slog.String("key", issue.Key), slog.String("label", groupID))
+		}
+	}
+
+	if len(r.conf.UpdateFields) > 0 {
+		fields := make(map[string]interface{}, len(r.conf.UpdateFields))
+		for name, text := range r.conf.UpdateFields {
+			rendered := r.tmpl.Execute(text, data, logger)
+			if err := r.tmpl.Err(); err != nil {
+				return false, err
+			}
+			fields[name] = updateFieldValue(name, rendered)
+		}
+		if resp, err := r.client.Issue.UpdateIssue(issue.Key, map[string]interface{}{"fields": fields}); err != nil {
+			return r.handleJiraError("Issue.UpdateIssue", resp, err, logger)
+		}
+		logger.LogAttrs(context.Background(), slog.LevelInfo, "issue unresolved, updated fields", slog.String("key", issue.Key), slog.String("label", groupID), slog.Any("fields", r.conf.UpdateFields))
+	}
+
+	return false, nil
+}
+
+// updateFieldValue shapes a rendered update_fields template into whatever
+// JSON structure JIRA's update API expects for that field name. "priority"
+// takes an object with a "name" key and "labels" takes an array (split on
+// whitespace, JIRA labels can't contain spaces); every other field is
+// assumed to be genuinely string-valued and passed through as-is.
+func updateFieldValue(name, rendered string) interface{} {
+	switch name {
+	case "priority":
+		return map[string]interface{}{"name": rendered}
+	case "labels":
+		return strings.Fields(rendered)
+	default:
+		return rendered
+	}
+}
+
+// lastCommentMarker returns the common.CommentHashMarker embedded in the
+// most recent comment on issue, or "" if it has no comments or none carry one.
+func lastCommentMarker(issue *jira.Issue) string {
+	if issue.Fields == nil || issue.Fields.Comments == nil || len(issue.Fields.Comments.Comments) == 0 {
+		return ""
+	}
+	comments := issue.Fields.Comments.Comments
+	return common.LastCommentMarker(comments[len(comments)-1].Body)
+}
+
+func (r *Notifier) create(issue *jira.Issue, logger *slog.Logger) (bool, error) {
+	logger.LogAttrs(context.Background(), slog.LevelDebug, "create", slog.Any("issue", *issue))
+	newIssue, resp, err := r.client.Issue.Create(issue)
+	if err != nil {
+		return r.handleJiraError("Issue.Create", resp, err, logger)
+	}
+	*issue = *newIssue
+
+	logger.LogAttrs(context.Background(), slog.LevelDebug, "create done", slog.String("key", issue.Key), slog.String("id", issue.ID))
+	return false, nil
+}
+
+// handleJiraError builds an error from a failed JIRA API call. For 401/403
+// responses it appends a hint about the receiver's configured auth mode,
+// since those are almost always a misconfigured credential rather than a
+// transient failure.
+func (r *Notifier) handleJiraError(api string, resp *jira.Response, err error, logger *slog.Logger) (bool, error) {
+	if resp == nil || resp.Request == nil {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "handleJiraError", slog.String("api", api), slog.Any("err", err))
+	} else {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "handleJiraError", slog.String("api", api), slog.Any("err", err), slog.Any("url", resp.Request.URL))
+	}
+
+	if resp != nil && resp.StatusCode/100 != 2 {
+		retry := common.ClassifyRetry(resp.StatusCode)
+		body, _ := ioutil.ReadAll(resp.Body)
+		// go-jira error message is not particularly helpful, replace it
+		msg := fmt.Sprintf("JIRA request %s returned status %s, body %q", resp.Request.URL, resp.Status, string(body))
+		if resp.StatusCode == 401 || resp.StatusCode == 403 {
+			msg += fmt.Sprintf(" (auth mode %q configured, double check credentials for that mode)", r.authMode())
+		}
+		return retry, errors.New(msg)
+	}
+	return false, fmt.Errorf("JIRA request %s failed: %s", api, err)
+}
+
+// authMode returns the effective auth mode for this receiver, accounting for
+// the deprecated User/Password fields.
+func (r *Notifier) authMode() config.AuthMode {
+	if r.conf.Auth.Mode != "" {
+		return r.conf.Auth.Mode
+	}
+	return config.AuthBasic
+}
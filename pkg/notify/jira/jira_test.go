@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira"
+	"github.com/free/jiralert/pkg/config"
+)
+
+func TestUpdateFieldValue(t *testing.T) {
+	cases := []struct {
+		name     string
+		field    string
+		rendered string
+		want     interface{}
+	}{
+		{
+			name:     "priority becomes a name object",
+			field:    "priority",
+			rendered: "Highest",
+			want:     map[string]interface{}{"name": "Highest"},
+		},
+		{
+			name:     "labels becomes a string array",
+			field:    "labels",
+			rendered: "incident security-review",
+			want:     []string{"incident", "security-review"},
+		},
+		{
+			name:     "unknown fields pass through as a string",
+			field:    "customfield_10010",
+			rendered: "some value",
+			want:     "some value",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := updateFieldValue(c.field, c.rendered)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("updateFieldValue(%q, %q) = %#v, want %#v", c.field, c.rendered, got, c.want)
+			}
+		})
+	}
+}
+
+// TestSearchRequestsAssigneeField is a regression guard for resolve's
+// "don't auto-resolve an assigned issue" safety check: that check only
+// works because search asks JIRA for the "assignee" field. Drop it from
+// the Fields list (as it briefly was before comment-dedup support needed
+// it too) and issue.Fields.Assignee silently comes back nil for every
+// issue, and ResolveIgnoreAssignee's guard becomes a no-op.
+func TestSearchRequestsAssigneeField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields := r.URL.Query().Get("fields")
+		if !strings.Contains(fields, "assignee") {
+			t.Errorf("search request fields=%q does not include \"assignee\"", fields)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"issues": []jira.Issue{}, "startAt": 0, "maxResults": 2, "total": 0})
+	}))
+	defer server.Close()
+
+	client, err := jira.NewClient(nil, server.URL)
+	if err != nil {
+		t.Fatalf("building jira client: %v", err)
+	}
+	r := &Notifier{
+		conf:   &config.ReceiverConfig{GroupFieldName: "Group ID"},
+		client: client,
+	}
+
+	if _, _, err := r.search("OPS", "ALERT{alertname=\"Watchdog\"}", slog.Default()); err != nil {
+		t.Fatalf("search: %v", err)
+	}
+}
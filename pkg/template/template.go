@@ -0,0 +1,61 @@
+// Package template renders JIRA field values from Alertmanager data using Go templates.
+package template
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"text/template"
+
+	"github.com/free/jiralert/pkg/alertmanager"
+)
+
+// Template wraps a parsed text/template and accumulates the first error seen
+// across a batch of Execute calls, so callers can render several fields and
+// check for failure once at the end.
+type Template struct {
+	tmpl *template.Template
+	err  error
+}
+
+// LoadTemplate parses the named template file.
+func LoadTemplate(path string) (*Template, error) {
+	tmpl, err := template.New("").Option("missingkey=zero").ParseFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{tmpl: tmpl}, nil
+}
+
+// Execute renders text as a template against data, using the named templates
+// loaded from the template file. If text does not reference a named
+// template, it is executed as an inline template string.
+func (t *Template) Execute(text string, data *alertmanager.Data, logger *slog.Logger) string {
+	if t.err != nil {
+		return ""
+	}
+
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		t.err = err
+		return ""
+	}
+	tmpl, err = tmpl.New("").Parse(text)
+	if err != nil {
+		t.err = err
+		return ""
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		logger.LogAttrs(context.Background(), slog.LevelDebug, "template execution failed", slog.Any("err", err), slog.String("template", text))
+		t.err = err
+		return ""
+	}
+	return buf.String()
+}
+
+// Err returns the first error encountered by Execute, if any.
+func (t *Template) Err() error {
+	return t.err
+}